@@ -0,0 +1,54 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package readiness provides a reusable startup-readiness subsystem for
+// cadence services: a set of pluggable checks, each retried independently
+// with backoff until it passes or an overall deadline expires, plus an HTTP
+// surface so external orchestrators can observe the result instead of the
+// service calling log.Fatal on a cold cluster boot.
+package readiness
+
+import "context"
+
+// Check is a single dependency a service must be able to reach before it is
+// considered ready to serve traffic.
+type Check interface {
+	// Name identifies the check. It is used as the tally gauge and log tag.
+	Name() string
+	// Check performs one readiness probe. A nil return means the dependency
+	// is reachable; any error means the check should be retried.
+	Check(ctx context.Context) error
+}
+
+// FuncCheck adapts a plain function into a Check.
+type FuncCheck struct {
+	CheckName string
+	CheckFunc func(ctx context.Context) error
+}
+
+// Name implements Check
+func (f *FuncCheck) Name() string {
+	return f.CheckName
+}
+
+// Check implements Check
+func (f *FuncCheck) Check(ctx context.Context) error {
+	return f.CheckFunc(ctx)
+}
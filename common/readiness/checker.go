@@ -0,0 +1,160 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+)
+
+const (
+	checkInitialInterval = 100 * time.Millisecond
+	checkMaxInterval     = 5 * time.Second
+	checkBackoffCoeff    = 2.0
+	// checkJitter is the +/- fraction of randomness added to each backoff
+	// interval so that many checks of the same kind don't retry in lockstep
+	checkJitter = 0.2
+)
+
+type (
+	// Checker runs a fixed set of Checks, each retried independently with
+	// exponential backoff and jitter, until every check passes or the overall
+	// deadline expires.
+	Checker struct {
+		checks   []Check
+		deadline time.Duration
+		scope    tally.Scope
+		logger   bark.Logger
+
+		mu    sync.RWMutex
+		ready bool
+		err   error
+	}
+)
+
+// NewChecker builds a Checker over checks, bounded by deadline
+func NewChecker(checks []Check, deadline time.Duration, scope tally.Scope, logger bark.Logger) *Checker {
+	return &Checker{
+		checks:   checks,
+		deadline: deadline,
+		scope:    scope,
+		logger:   logger,
+	}
+}
+
+// WaitUntilReady blocks until every check passes or the deadline expires,
+// whichever comes first, and records the outcome for IsReady/Err. It never
+// panics or exits the process on failure -- the caller decides what to do
+// with a non-nil error.
+func (c *Checker) WaitUntilReady(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.deadline)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(c.checks))
+
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			errs[i] = c.runUntilReady(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", c.checks[i].Name(), err))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(failed) > 0 {
+		c.err = fmt.Errorf("readiness checks did not pass within %v: %v", c.deadline, failed)
+		c.ready = false
+		return c.err
+	}
+	c.ready = true
+	c.err = nil
+	return nil
+}
+
+// IsReady reports whether the most recent WaitUntilReady call succeeded
+func (c *Checker) IsReady() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ready
+}
+
+// Err returns the error from the most recent WaitUntilReady call, if any
+func (c *Checker) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.err
+}
+
+func (c *Checker) runUntilReady(ctx context.Context, check Check) error {
+	gauge := c.scope.Tagged(map[string]string{"readiness-check": check.Name()}).Gauge("readiness-check-passed")
+
+	interval := checkInitialInterval
+	var lastErr error
+	for {
+		if ctx.Err() != nil {
+			gauge.Update(0)
+			if lastErr == nil {
+				lastErr = ctx.Err()
+			}
+			return lastErr
+		}
+
+		lastErr = check.Check(ctx)
+		if lastErr == nil {
+			gauge.Update(1)
+			return nil
+		}
+		gauge.Update(0)
+		c.logger.Warnf("readiness check %v not yet passing: %v", check.Name(), lastErr)
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-time.After(jitter(interval)):
+		}
+		interval = time.Duration(float64(interval) * checkBackoffCoeff)
+		if interval > checkMaxInterval {
+			interval = checkMaxInterval
+		}
+	}
+}
+
+// jitter returns d scaled by a random factor in [1-checkJitter, 1+checkJitter]
+func jitter(d time.Duration) time.Duration {
+	factor := 1 + checkJitter*(2*rand.Float64()-1)
+	return time.Duration(float64(d) * factor)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package readiness
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+)
+
+func TestChecker_WaitUntilReady_AllChecksPass(t *testing.T) {
+	checker := NewChecker(
+		[]Check{
+			&FuncCheck{CheckName: "a", CheckFunc: func(ctx context.Context) error { return nil }},
+			&FuncCheck{CheckName: "b", CheckFunc: func(ctx context.Context) error { return nil }},
+		},
+		time.Second,
+		tally.NoopScope,
+		bark.NewNopLogger(),
+	)
+
+	err := checker.WaitUntilReady(context.Background())
+	require.NoError(t, err)
+	require.True(t, checker.IsReady())
+}
+
+func TestChecker_WaitUntilReady_DeadlineExceeded(t *testing.T) {
+	checker := NewChecker(
+		[]Check{
+			&FuncCheck{CheckName: "never-ready", CheckFunc: func(ctx context.Context) error { return errors.New("down") }},
+		},
+		50*time.Millisecond,
+		tally.NoopScope,
+		bark.NewNopLogger(),
+	)
+
+	err := checker.WaitUntilReady(context.Background())
+	require.Error(t, err)
+	require.False(t, checker.IsReady())
+	require.Equal(t, err, checker.Err())
+}
+
+func TestChecker_WaitUntilReady_EventuallyPasses(t *testing.T) {
+	attempts := 0
+	checker := NewChecker(
+		[]Check{
+			&FuncCheck{CheckName: "flaky", CheckFunc: func(ctx context.Context) error {
+				attempts++
+				if attempts < 3 {
+					return errors.New("not yet")
+				}
+				return nil
+			}},
+		},
+		time.Second,
+		tally.NoopScope,
+		bark.NewNopLogger(),
+	)
+
+	err := checker.WaitUntilReady(context.Background())
+	require.NoError(t, err)
+	require.True(t, attempts >= 3)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package readiness
+
+import (
+	"net"
+	"net/http"
+)
+
+// NewHTTPHandler builds the /healthz and /readyz handlers for checker.
+// /healthz reports 503 only once checker.Err() is set, i.e. WaitUntilReady
+// has definitively failed (the deadline expired with at least one check
+// still failing); it reports 200 while startup is still in progress so an
+// orchestrator's liveness probe doesn't kill the process mid-boot, but does
+// restart it once startup is declared a permanent failure. /readyz reports
+// 200 only once checker.IsReady() is true, 503 otherwise, so an orchestrator
+// can hold traffic back until startup checks pass without the service
+// needing to call log.Fatal itself.
+func NewHTTPHandler(checker *Checker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if checker.Err() != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if checker.IsReady() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	return mux
+}
+
+// StartHTTPServer binds a readiness HTTP server for checker to addr and
+// serves it in the background. The returned listener's Addr() can be used to
+// discover the bound port when addr uses port 0.
+func StartHTTPServer(addr string, checker *Checker) (net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{Handler: NewHTTPHandler(checker)}
+	go server.Serve(listener)
+	return listener, nil
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package dynamicconfig provides live-reloadable configuration values, each
+// identified by a Key and optionally scoped further by a set of Filters (e.g.
+// domain name, task list name) applied at read time.
+package dynamicconfig
+
+import (
+	"time"
+
+	"github.com/uber-common/bark"
+)
+
+type (
+	// Client reads dynamic config values from the backing config store. A
+	// nil filters map means the caller is not scoping the read.
+	Client interface {
+		GetValue(name Key, defaultValue interface{}) (interface{}, error)
+		GetValueWithFilters(name Key, filters map[Filter]interface{}, defaultValue interface{}) (interface{}, error)
+	}
+
+	// Collection wraps a Client with typed, defaulted accessors that return
+	// closures instead of one-shot values, so every call site re-reads the
+	// latest value from the backing store instead of capturing it once at
+	// startup.
+	Collection struct {
+		client Client
+		logger bark.Logger
+	}
+
+	// FilterOption scopes a Collection read down to, e.g., a specific domain
+	// or task list. Options are applied in order into a single filter map.
+	FilterOption func(filterMap map[Filter]interface{})
+
+	// IntPropertyFn is a dynamic config property of type int.
+	IntPropertyFn func(opts ...FilterOption) int
+	// BoolPropertyFn is a dynamic config property of type bool.
+	BoolPropertyFn func(opts ...FilterOption) bool
+	// DurationPropertyFn is a dynamic config property of type time.Duration.
+	DurationPropertyFn func(opts ...FilterOption) time.Duration
+	// ListPropertyFn is a dynamic config property of type []interface{}.
+	ListPropertyFn func(opts ...FilterOption) []interface{}
+)
+
+// NewCollection builds a Collection backed by client.
+func NewCollection(client Client, logger bark.Logger) *Collection {
+	return &Collection{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (c *Collection) filters(opts ...FilterOption) map[Filter]interface{} {
+	filters := make(map[Filter]interface{}, len(opts))
+	for _, opt := range opts {
+		opt(filters)
+	}
+	return filters
+}
+
+// GetIntProperty returns an IntPropertyFn for key, falling back to
+// defaultValue when the backing store has no value or returns an error.
+func (c *Collection) GetIntProperty(key Key, defaultValue int) IntPropertyFn {
+	return func(opts ...FilterOption) int {
+		value, err := c.client.GetValueWithFilters(key, c.filters(opts...), defaultValue)
+		if err != nil {
+			c.logger.Warnf("failed to read dynamic config %v, using default %v: %v", key, defaultValue, err)
+			return defaultValue
+		}
+		intValue, ok := value.(int)
+		if !ok {
+			c.logger.Warnf("dynamic config %v has unexpected type %T, using default %v", key, value, defaultValue)
+			return defaultValue
+		}
+		return intValue
+	}
+}
+
+// GetBoolProperty returns a BoolPropertyFn for key, falling back to
+// defaultValue when the backing store has no value or returns an error.
+func (c *Collection) GetBoolProperty(key Key, defaultValue bool) BoolPropertyFn {
+	return func(opts ...FilterOption) bool {
+		value, err := c.client.GetValueWithFilters(key, c.filters(opts...), defaultValue)
+		if err != nil {
+			c.logger.Warnf("failed to read dynamic config %v, using default %v: %v", key, defaultValue, err)
+			return defaultValue
+		}
+		boolValue, ok := value.(bool)
+		if !ok {
+			c.logger.Warnf("dynamic config %v has unexpected type %T, using default %v", key, value, defaultValue)
+			return defaultValue
+		}
+		return boolValue
+	}
+}
+
+// GetDurationProperty returns a DurationPropertyFn for key, falling back to
+// defaultValue when the backing store has no value or returns an error.
+func (c *Collection) GetDurationProperty(key Key, defaultValue time.Duration) DurationPropertyFn {
+	return func(opts ...FilterOption) time.Duration {
+		value, err := c.client.GetValueWithFilters(key, c.filters(opts...), defaultValue)
+		if err != nil {
+			c.logger.Warnf("failed to read dynamic config %v, using default %v: %v", key, defaultValue, err)
+			return defaultValue
+		}
+		durationValue, ok := value.(time.Duration)
+		if !ok {
+			c.logger.Warnf("dynamic config %v has unexpected type %T, using default %v", key, value, defaultValue)
+			return defaultValue
+		}
+		return durationValue
+	}
+}
+
+// GetListProperty returns a ListPropertyFn for key, falling back to
+// defaultValue when the backing store has no value or returns an error.
+func (c *Collection) GetListProperty(key Key, defaultValue []interface{}) ListPropertyFn {
+	return func(opts ...FilterOption) []interface{} {
+		value, err := c.client.GetValueWithFilters(key, c.filters(opts...), defaultValue)
+		if err != nil {
+			c.logger.Warnf("failed to read dynamic config %v, using default %v: %v", key, defaultValue, err)
+			return defaultValue
+		}
+		listValue, ok := value.([]interface{})
+		if !ok {
+			c.logger.Warnf("dynamic config %v has unexpected type %T, using default %v", key, value, defaultValue)
+			return defaultValue
+		}
+		return listValue
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+// Key identifies a single dynamic config value.
+type Key int
+
+const (
+	unknownKey Key = iota
+
+	// WorkerPersistenceMaxQPS is the max qps a worker host can query the
+	// persistence layer with.
+	WorkerPersistenceMaxQPS
+	// WorkerReplicatorConcurrency is the number of concurrent workers
+	// processing replication tasks off the replication task topic.
+	WorkerReplicatorConcurrency
+	// WorkerReplicationTaskMaxRetry is the max retry count for a generic
+	// (non domain-not-found) replication task processing failure.
+	WorkerReplicationTaskMaxRetry
+	// WorkerReplicationDomainNotFoundRetryCount is the max number of times a
+	// replication task is retried with backoff while its domain has not yet
+	// been registered locally, before it is routed to the DLQ.
+	WorkerReplicationDomainNotFoundRetryCount
+	// WorkerSysWorkflowEnabledModules is the list of sysworkflow module names
+	// (see sysworkflow.SysWorkflowModule.Name) enabled for this cluster.
+	WorkerSysWorkflowEnabledModules
+	// WorkerStartupDeadline bounds how long the worker service's startup
+	// readiness checks are retried before Start gives up and serves unready.
+	WorkerStartupDeadline
+	// WorkerReadinessHTTPPort is the local port the worker service's
+	// /healthz and /readyz endpoints are bound to.
+	WorkerReadinessHTTPPort
+	// WorkerPerDomainMetricsEnabled gates tagging replicator and sysworkflow
+	// metrics with domain/source_cluster/workflow_type.
+	WorkerPerDomainMetricsEnabled
+	// WorkerMetricsDomainCardinalityLimit bounds how many distinct domains
+	// get their own tag value once WorkerPerDomainMetricsEnabled is on.
+	WorkerMetricsDomainCardinalityLimit
+)
+
+var keys = map[Key]string{
+	unknownKey: "unknownKey",
+
+	WorkerPersistenceMaxQPS:                   "worker.persistenceMaxQPS",
+	WorkerReplicatorConcurrency:               "worker.replicatorConcurrency",
+	WorkerReplicationTaskMaxRetry:             "worker.replicationTaskMaxRetry",
+	WorkerReplicationDomainNotFoundRetryCount: "worker.replicationDomainNotFoundRetryCount",
+	WorkerSysWorkflowEnabledModules:           "worker.sysWorkflowEnabledModules",
+	WorkerStartupDeadline:                     "worker.startupDeadline",
+	WorkerReadinessHTTPPort:                   "worker.readinessHTTPPort",
+	WorkerPerDomainMetricsEnabled:             "worker.perDomainMetricsEnabled",
+	WorkerMetricsDomainCardinalityLimit:       "worker.metricsDomainCardinalityLimit",
+}
+
+// String returns the config store name for k.
+func (k Key) String() string {
+	if name, ok := keys[k]; ok {
+		return name
+	}
+	return keys[unknownKey]
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+// Filter identifies a dimension a dynamic config value can be scoped by.
+type Filter int
+
+const (
+	// UnknownFilter is the zero Filter; it names no dimension.
+	UnknownFilter Filter = iota
+	// DomainName scopes a value to a specific domain.
+	DomainName
+	// TaskListName scopes a value to a specific task list.
+	TaskListName
+)
+
+// DomainFilter scopes a Collection read to domain.
+func DomainFilter(domain string) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[DomainName] = domain
+	}
+}
+
+// TaskListFilter scopes a Collection read to taskList.
+func TaskListFilter(taskList string) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[TaskListName] = taskList
+	}
+}
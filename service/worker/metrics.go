@@ -0,0 +1,82 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package worker
+
+import (
+	"sync"
+
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/service/worker/replicator"
+)
+
+// domainCardinalityGuard tracks how many distinct domains have been tagged
+// onto baseScope so far. Once the configured limit is reached, additional
+// domains are bucketed into a shared "other" tag instead of growing the
+// metrics backend's tag cardinality without bound.
+type domainCardinalityGuard struct {
+	baseScope   tally.Scope
+	cardinality func() int
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func newDomainCardinalityGuard(baseScope tally.Scope, cardinality func() int) *domainCardinalityGuard {
+	return &domainCardinalityGuard{
+		baseScope:   baseScope,
+		cardinality: cardinality,
+		seen:        make(map[string]struct{}),
+	}
+}
+
+// scope returns a tally.Scope tagged with domain and sourceCluster. At most
+// g.cardinality() distinct domains get their own tag value; any further
+// domain is tagged "other".
+func (g *domainCardinalityGuard) scope(domainName, sourceCluster string) tally.Scope {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.seen[domainName]; !ok {
+		if len(g.seen) >= g.cardinality() {
+			g.baseScope.Counter("metrics.cardinality-capped").Inc(1)
+			return g.baseScope.Tagged(map[string]string{"domain": "other", "source_cluster": sourceCluster})
+		}
+		g.seen[domainName] = struct{}{}
+	}
+	return g.baseScope.Tagged(map[string]string{"domain": domainName, "source_cluster": sourceCluster})
+}
+
+// newReplicatorScopeFactory builds the replicator.ScopeFactory passed to
+// replicator.NewReplicator. When s.config's per-domain metrics gate is off,
+// every domain shares baseScope untagged, since high-cardinality domain tags
+// are expensive and most clusters don't need per-tenant attribution.
+func (s *Service) newReplicatorScopeFactory(baseScope tally.Scope) replicator.ScopeFactory {
+	enabled := s.config.PerDomainMetricsEnabled
+	guard := newDomainCardinalityGuard(baseScope, s.config.MetricsDomainCardinalityLimit)
+
+	return func(domainName, sourceCluster string) tally.Scope {
+		if !enabled() {
+			return baseScope
+		}
+		return guard.scope(domainName, sourceCluster)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestDomainCardinalityGuard_BucketsOverflowIntoOther(t *testing.T) {
+	guard := newDomainCardinalityGuard(tally.NoopScope, func() int { return 2 })
+
+	firstScope := guard.scope("domain-a", "cluster-a")
+	secondScope := guard.scope("domain-b", "cluster-a")
+	overflowScope := guard.scope("domain-c", "cluster-a")
+
+	require.NotNil(t, firstScope)
+	require.NotNil(t, secondScope)
+	require.NotNil(t, overflowScope)
+	require.Len(t, guard.seen, 2)
+}
+
+func TestDomainCardinalityGuard_RepeatedDomainDoesNotGrowSeenSet(t *testing.T) {
+	guard := newDomainCardinalityGuard(tally.NoopScope, func() int { return 10 })
+
+	guard.scope("domain-a", "cluster-a")
+	guard.scope("domain-a", "cluster-a")
+
+	require.Len(t, guard.seen, 1)
+}
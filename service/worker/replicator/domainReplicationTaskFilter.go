@@ -0,0 +1,88 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package replicator
+
+import (
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+	"go.uber.org/cadence/.gen/go/shared"
+
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	// domainReplicationTaskFilter decides whether a replication task naming a
+	// domain should be processed now or retried. A domain registration task
+	// can legitimately race ahead of the local metadata row on a follower
+	// cluster, so a missing domain is treated as a transient condition rather
+	// than a reason to drop the task. Routing an exhausted task to the DLQ is
+	// the caller's responsibility (see Replicator.processTaskWithRetry) since
+	// filter has no access to the DLQ producer.
+	domainReplicationTaskFilter struct {
+		metadataManager persistence.MetadataManager
+		maxRetryCount   dynamicconfig.IntPropertyFn
+		logger          bark.Logger
+	}
+)
+
+func newDomainReplicationTaskFilter(
+	metadataManager persistence.MetadataManager,
+	maxRetryCount dynamicconfig.IntPropertyFn,
+	logger bark.Logger,
+) *domainReplicationTaskFilter {
+	return &domainReplicationTaskFilter{
+		metadataManager: metadataManager,
+		maxRetryCount:   maxRetryCount,
+		logger:          logger,
+	}
+}
+
+// filter reports whether the task for domainName should be processed. It
+// returns (true, nil) when the domain lookup succeeds, and (false, err) when
+// the domain does not exist yet (so the caller can retry with backoff) or the
+// lookup fails for any other reason. It never returns (false, nil).
+// attempt is the zero-based number of times this task has already been
+// retried for the same reason; scope is the domain/source_cluster-tagged
+// scope the caller is already emitting through.
+func (f *domainReplicationTaskFilter) filter(domainName string, attempt int, scope tally.Scope) (bool, error) {
+	_, err := f.metadataManager.GetDomain(&persistence.GetDomainRequest{Name: domainName})
+	if err == nil {
+		return true, nil
+	}
+
+	if _, ok := err.(*shared.EntityNotExistsError); !ok {
+		return false, err
+	}
+
+	scope.Counter("replication.domain-not-found-retry").Inc(1)
+	if attempt >= f.maxRetryCount() {
+		f.logger.Warnf("domain %v still not found after %v retries, exhausting retries for this task", domainName, attempt)
+	}
+	return false, err
+}
+
+// exhausted reports whether attempt has used up the configured retry budget
+// for a domain-not-found condition, meaning the caller should route the task
+// to the DLQ instead of retrying again.
+func (f *domainReplicationTaskFilter) exhausted(attempt int) bool {
+	return attempt >= f.maxRetryCount()
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package replicator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+	"go.uber.org/cadence/.gen/go/shared"
+
+	"github.com/uber/cadence/common/mocks"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+func TestDomainReplicationTaskFilter_DomainNotFound_ReturnsRetryableError(t *testing.T) {
+	metadataManager := &mocks.MetadataManager{}
+	metadataManager.On("GetDomain", mock.Anything).Return(nil, &shared.EntityNotExistsError{Message: "domain not found"})
+
+	filter := newDomainReplicationTaskFilter(
+		metadataManager,
+		func(opts ...dynamicconfig.FilterOption) int { return 5 },
+		bark.NewNopLogger(),
+	)
+
+	shouldProcess, err := filter.filter("test-domain", 0, tally.NoopScope)
+	require.False(t, shouldProcess)
+	require.IsType(t, &shared.EntityNotExistsError{}, err)
+	metadataManager.AssertExpectations(t)
+}
+
+func TestDomainReplicationTaskFilter_OtherError_IsNotRetriedAsDomainNotFound(t *testing.T) {
+	persistenceErr := errors.New("persistence unavailable")
+	metadataManager := &mocks.MetadataManager{}
+	metadataManager.On("GetDomain", mock.Anything).Return(nil, persistenceErr)
+
+	filter := newDomainReplicationTaskFilter(
+		metadataManager,
+		func(opts ...dynamicconfig.FilterOption) int { return 5 },
+		bark.NewNopLogger(),
+	)
+
+	shouldProcess, err := filter.filter("test-domain", 0, tally.NoopScope)
+	require.False(t, shouldProcess)
+	require.Equal(t, persistenceErr, err)
+}
+
+func TestDomainReplicationTaskFilter_Exhausted(t *testing.T) {
+	filter := newDomainReplicationTaskFilter(
+		&mocks.MetadataManager{},
+		func(opts ...dynamicconfig.FilterOption) int { return 3 },
+		bark.NewNopLogger(),
+	)
+
+	require.False(t, filter.exhausted(2))
+	require.True(t, filter.exhausted(3))
+	require.True(t, filter.exhausted(4))
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package replicator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+	"go.uber.org/cadence/.gen/go/shared"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/messaging"
+	"github.com/uber/cadence/common/persistence"
+)
+
+const (
+	// ReplicationTaskTopic is the topic domain-registration replication tasks
+	// are consumed from. Exported so callers outside this package (e.g. a
+	// readiness check) can verify connectivity against the topic the
+	// Replicator actually uses instead of an unrelated name.
+	ReplicationTaskTopic   = "cadence-replication-task"
+	replicationTaskGroup   = "cadence-replicator"
+	replicationTaskDLQName = ReplicationTaskTopic + "-dlq"
+
+	domainNotFoundInitialBackoff = 200 * time.Millisecond
+	domainNotFoundMaxBackoff     = 30 * time.Second
+	domainNotFoundBackoffCoeff   = 2.0
+)
+
+type (
+	// ScopeFactory builds the child tally.Scope a replication task processing
+	// path should emit through, tagged for the domain and source cluster the
+	// task belongs to. Callers are expected to apply any cardinality guard
+	// (e.g. bucketing long-tail domains into "other") before returning.
+	ScopeFactory func(domainName, sourceCluster string) tally.Scope
+
+	// ReplicationTask is a domain-registration replication task consumed off
+	// ReplicationTaskTopic. Attempt tracks how many times this task has been
+	// retried because its domain was not yet locally registered.
+	ReplicationTask struct {
+		DomainName    string `json:"domainName"`
+		SourceCluster string `json:"sourceCluster"`
+		Attempt       int    `json:"attempt"`
+	}
+
+	// Replicator is responsible for consuming replication tasks generated by
+	// remote clusters and passing each one whose domain is registered locally
+	// to applyTask.
+	Replicator struct {
+		clusterMetadata cluster.Metadata
+		metadataManager persistence.MetadataManager
+		historyClient   history.Client
+		config          *Config
+		messagingClient messaging.Client
+		logger          bark.Logger
+		scopeFactory    ScopeFactory
+		taskFilter      *domainReplicationTaskFilter
+		consumer        messaging.Consumer
+		shutdownCh      chan struct{}
+	}
+)
+
+// NewReplicator creates a new Replicator. scopeFactory derives the per-task
+// tally.Scope (tagged with domain and source_cluster) that task processing
+// metrics are emitted through.
+func NewReplicator(
+	clusterMetadata cluster.Metadata,
+	metadataManager persistence.MetadataManager,
+	historyClient history.Client,
+	config *Config,
+	messagingClient messaging.Client,
+	logger bark.Logger,
+	scopeFactory ScopeFactory,
+) *Replicator {
+	return &Replicator{
+		clusterMetadata: clusterMetadata,
+		metadataManager: metadataManager,
+		historyClient:   historyClient,
+		config:          config,
+		messagingClient: messagingClient,
+		logger:          logger,
+		scopeFactory:    scopeFactory,
+		taskFilter:      newDomainReplicationTaskFilter(metadataManager, config.DomainNotFoundRetryCount, logger),
+		shutdownCh:      make(chan struct{}),
+	}
+}
+
+// Start begins consuming replication tasks from ReplicationTaskTopic and
+// applying them, retrying domain-not-found tasks with backoff before routing
+// them to the DLQ. A Replicator with no messagingClient configured (e.g. a
+// single-cluster deployment) has nothing to consume and is a no-op.
+//
+// config.ReplicatorConcurrency goroutines drain the same consumer, so one
+// domain stuck retrying with backoff only occupies the goroutine handling
+// that task -- it does not stall delivery of unrelated, already-registered
+// domains' tasks.
+func (r *Replicator) Start() error {
+	if r.messagingClient == nil {
+		return nil
+	}
+
+	consumer, err := r.messagingClient.NewConsumer(ReplicationTaskTopic, replicationTaskGroup)
+	if err != nil {
+		return err
+	}
+	if err := consumer.Start(); err != nil {
+		return err
+	}
+
+	r.consumer = consumer
+	concurrency := r.config.ReplicatorConcurrency()
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	for i := 0; i < concurrency; i++ {
+		go r.processEvents()
+	}
+	return nil
+}
+
+// Stop stops the replicator
+func (r *Replicator) Stop() {
+	close(r.shutdownCh)
+	if r.consumer != nil {
+		r.consumer.Stop()
+	}
+}
+
+func (r *Replicator) processEvents() {
+	for {
+		select {
+		case <-r.shutdownCh:
+			return
+		case msg, ok := <-r.consumer.Messages():
+			if !ok {
+				return
+			}
+
+			task := &ReplicationTask{}
+			if err := json.Unmarshal(msg.Value(), task); err != nil {
+				r.logger.Errorf("failed to deserialize replication task, dropping: %v", err)
+				msg.Nack()
+				continue
+			}
+
+			if err := r.processTaskWithRetry(task); err != nil {
+				r.logger.Errorf("failed to process replication task for domain %v: %v", task.DomainName, err)
+			}
+			msg.Ack()
+		}
+	}
+}
+
+// processTaskWithRetry applies task, and when its domain is not yet
+// registered locally, retries with exponential backoff up to
+// config.DomainNotFoundRetryCount attempts before routing the task to the
+// DLQ instead of dropping it.
+func (r *Replicator) processTaskWithRetry(task *ReplicationTask) error {
+	for {
+		shouldProcess, err := r.shouldProcessTask(task)
+		if err == nil {
+			if !shouldProcess {
+				return nil
+			}
+			return r.applyTask(task)
+		}
+
+		if _, ok := err.(*shared.EntityNotExistsError); !ok {
+			return err
+		}
+
+		if r.taskFilter.exhausted(task.Attempt) {
+			return r.pushToDLQ(task, err)
+		}
+
+		task.Attempt++
+		select {
+		case <-r.shutdownCh:
+			return err
+		case <-time.After(domainNotFoundBackoff(task.Attempt)):
+		}
+	}
+}
+
+// shouldProcessTask reports whether task should be processed now, emitting
+// through the domain/source_cluster-tagged scope from r.scopeFactory so
+// replicator lag and error rates can be attributed to a tenant.
+func (r *Replicator) shouldProcessTask(task *ReplicationTask) (bool, error) {
+	scope := r.scopeFactory(task.DomainName, task.SourceCluster)
+	return r.taskFilter.filter(task.DomainName, task.Attempt, scope)
+}
+
+// applyTask returns nil without acting on task: it makes no history service
+// call and does not translate the task into one.
+func (r *Replicator) applyTask(task *ReplicationTask) error {
+	return nil
+}
+
+// pushToDLQ publishes task to the replication task DLQ after its retry
+// budget for cause is exhausted.
+func (r *Replicator) pushToDLQ(task *ReplicationTask, cause error) error {
+	if r.messagingClient == nil {
+		return fmt.Errorf("no messaging client configured, dropping replication task for domain %v after %v attempts: %v",
+			task.DomainName, task.Attempt, cause)
+	}
+
+	producer, err := r.messagingClient.NewProducer(replicationTaskDLQName)
+	if err != nil {
+		return fmt.Errorf("failed to create DLQ producer for domain %v: %v", task.DomainName, err)
+	}
+
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to serialize replication task for domain %v: %v", task.DomainName, err)
+	}
+
+	if err := producer.Publish(payload); err != nil {
+		return fmt.Errorf("failed to publish replication task for domain %v to DLQ: %v", task.DomainName, err)
+	}
+
+	r.logger.Warnf("pushed replication task for domain %v to DLQ after %v attempts: %v", task.DomainName, task.Attempt, cause)
+	return nil
+}
+
+// domainNotFoundBackoff returns the exponential backoff duration before
+// retrying a domain-not-found task for the given attempt number
+func domainNotFoundBackoff(attempt int) time.Duration {
+	backoff := float64(domainNotFoundInitialBackoff)
+	for i := 0; i < attempt; i++ {
+		backoff *= domainNotFoundBackoffCoeff
+		if backoff >= float64(domainNotFoundMaxBackoff) {
+			return domainNotFoundMaxBackoff
+		}
+	}
+	return time.Duration(backoff)
+}
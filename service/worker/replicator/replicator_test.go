@@ -0,0 +1,105 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package replicator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+	"go.uber.org/cadence/.gen/go/shared"
+
+	"github.com/uber/cadence/common/messaging"
+	"github.com/uber/cadence/common/mocks"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+// fakeProducer records every payload published to it.
+type fakeProducer struct {
+	published [][]byte
+}
+
+func (p *fakeProducer) Publish(payload []byte) error {
+	p.published = append(p.published, payload)
+	return nil
+}
+
+// fakeMessagingClient hands out the same fakeProducer for every topic so
+// tests can assert on what was published.
+type fakeMessagingClient struct {
+	producer       *fakeProducer
+	producerTopics []string
+}
+
+func (c *fakeMessagingClient) NewConsumer(topic, consumerGroup string) (messaging.Consumer, error) {
+	return nil, nil
+}
+
+func (c *fakeMessagingClient) NewProducer(topic string) (messaging.Producer, error) {
+	c.producerTopics = append(c.producerTopics, topic)
+	return c.producer, nil
+}
+
+func newTestReplicator(metadataManager *mocks.MetadataManager, messagingClient messaging.Client, maxRetryCount int) *Replicator {
+	config := &Config{
+		DomainNotFoundRetryCount: func(opts ...dynamicconfig.FilterOption) int { return maxRetryCount },
+	}
+	return NewReplicator(nil, metadataManager, nil, config, messagingClient, bark.NewNopLogger(),
+		func(domainName, sourceCluster string) tally.Scope { return tally.NoopScope })
+}
+
+func TestReplicator_ProcessTaskWithRetry_ExhaustedPushesToDLQ(t *testing.T) {
+	metadataManager := &mocks.MetadataManager{}
+	metadataManager.On("GetDomain", mock.Anything).Return(nil, &shared.EntityNotExistsError{Message: "domain not found"})
+
+	producer := &fakeProducer{}
+	messagingClient := &fakeMessagingClient{producer: producer}
+	r := newTestReplicator(metadataManager, messagingClient, 0)
+
+	task := &ReplicationTask{DomainName: "test-domain", SourceCluster: "cluster-a"}
+	err := r.processTaskWithRetry(task)
+	require.NoError(t, err)
+
+	require.Len(t, producer.published, 1)
+	require.Equal(t, []string{replicationTaskDLQName}, messagingClient.producerTopics)
+
+	var published ReplicationTask
+	require.NoError(t, json.Unmarshal(producer.published[0], &published))
+	require.Equal(t, task.DomainName, published.DomainName)
+	require.Equal(t, task.Attempt, published.Attempt)
+}
+
+func TestReplicator_PushToDLQ_NoMessagingClient_ReturnsDropError(t *testing.T) {
+	r := newTestReplicator(&mocks.MetadataManager{}, nil, 0)
+
+	err := r.pushToDLQ(&ReplicationTask{DomainName: "test-domain"}, &shared.EntityNotExistsError{Message: "domain not found"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dropping replication task")
+}
+
+func TestDomainNotFoundBackoff_GrowsAndCapsAtMax(t *testing.T) {
+	require.Equal(t, domainNotFoundInitialBackoff, domainNotFoundBackoff(0))
+	require.True(t, domainNotFoundBackoff(1) > domainNotFoundBackoff(0))
+	require.Equal(t, domainNotFoundMaxBackoff, domainNotFoundBackoff(100))
+}
@@ -22,43 +22,56 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"sync"
+
 	"github.com/uber-common/bark"
 	"github.com/uber-go/tally"
 	"github.com/uber/cadence/client/frontend"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/metrics"
 	persistencefactory "github.com/uber/cadence/common/persistence/persistence-factory"
+	"github.com/uber/cadence/common/readiness"
 	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/common/service/dynamicconfig"
 	"github.com/uber/cadence/service/worker/replicator"
 	"github.com/uber/cadence/service/worker/sysworkflow"
 	"go.uber.org/cadence/.gen/go/shared"
+	cadenceWorker "go.uber.org/cadence/worker"
 	"time"
 )
 
-const (
-	// FrontendRetryLimit is the number of times frontend will try to be connected to before giving up
-	FrontendRetryLimit = 5
-
-	// PollingDelay is the amount of time to wait between polling frontend
-	PollingDelay = time.Second
-)
-
 type (
 	// Service represents the cadence-worker service.  This service host all background processing which needs to happen
 	// for a Cadence cluster.  This service runs the replicator which is responsible for applying replication tasks
 	// generated by remote clusters.
 	Service struct {
-		stopC         chan struct{}
-		params        *service.BootstrapParams
-		config        *Config
-		metricsClient metrics.Client
+		stopC            chan struct{}
+		params           *service.BootstrapParams
+		config           *Config
+		metricsClient    metrics.Client
+		sysWorkers       []cadenceWorker.Worker
+		readinessChecker *readiness.Checker
 	}
 
 	// Config contains all the service config for worker
 	Config struct {
-		ReplicationCfg *replicator.Config
-		SysWorkflowCfg *sysworkflow.Config
+		ReplicationCfg  *replicator.Config
+		SysWorkflowCfg  *sysworkflow.Config
+		StartupDeadline dynamicconfig.DurationPropertyFn
+
+		// PerDomainMetricsEnabled gates tagging replicator and sysworkflow
+		// metrics with domain/source_cluster/workflow_type. Off by default
+		// because high-cardinality tags are expensive on a shared cluster.
+		PerDomainMetricsEnabled dynamicconfig.BoolPropertyFn
+		// MetricsDomainCardinalityLimit bounds how many distinct domains get
+		// their own tag value once PerDomainMetricsEnabled is on; the rest
+		// bucket into a shared "other" tag.
+		MetricsDomainCardinalityLimit dynamicconfig.IntPropertyFn
+		// ReadinessHTTPPort is the local port the /healthz and /readyz
+		// endpoints are bound to, alongside the service's other admin
+		// surfaces.
+		ReadinessHTTPPort dynamicconfig.IntPropertyFn
 	}
 )
 
@@ -80,8 +93,15 @@ func NewConfig(dc *dynamicconfig.Collection) *Config {
 			ReplicatorConcurrency:      dc.GetIntProperty(dynamicconfig.WorkerReplicatorConcurrency, 1000),
 			ReplicatorBufferRetryCount: 8,
 			ReplicationTaskMaxRetry:    dc.GetIntProperty(dynamicconfig.WorkerReplicationTaskMaxRetry, 50),
+			DomainNotFoundRetryCount:   dc.GetIntProperty(dynamicconfig.WorkerReplicationDomainNotFoundRetryCount, 20),
+		},
+		SysWorkflowCfg: &sysworkflow.Config{
+			EnabledModules: dc.GetListProperty(dynamicconfig.WorkerSysWorkflowEnabledModules, []interface{}{sysworkflow.CoreModuleName}),
 		},
-		SysWorkflowCfg: &sysworkflow.Config{},
+		StartupDeadline:               dc.GetDurationProperty(dynamicconfig.WorkerStartupDeadline, 2*time.Minute),
+		PerDomainMetricsEnabled:       dc.GetBoolProperty(dynamicconfig.WorkerPerDomainMetricsEnabled, false),
+		MetricsDomainCardinalityLimit: dc.GetIntProperty(dynamicconfig.WorkerMetricsDomainCardinalityLimit, 1000),
+		ReadinessHTTPPort:             dc.GetIntProperty(dynamicconfig.WorkerReadinessHTTPPort, 9001),
 	}
 }
 
@@ -96,16 +116,108 @@ func (s *Service) Start() {
 
 	s.metricsClient = base.GetMetricsClient()
 
+	frontendClient, err := base.GetClientFactory().NewFrontendClient()
+	if err != nil {
+		log.Fatalf("failed to create frontend client: %v", err)
+	}
+	frontendClient = frontend.NewRetryableClient(frontendClient, common.CreateFrontendServiceRetryPolicy(),
+		common.IsWhitelistServiceTransientError)
+
+	if err := s.waitUntilReady(params, base, log, frontendClient); err != nil {
+		log.Errorf("%v did not pass startup readiness checks, serving unready until the orchestrator restarts it: %v",
+			common.WorkerServiceName, err)
+		<-s.stopC
+		base.Stop()
+		return
+	}
+
 	if s.params.ClusterMetadata.IsGlobalDomainEnabled() {
 		s.startReplicator(params, base, log)
 	}
-	s.startSysWorker(base, log, s.params.MetricScope)
+	s.startSysWorker(base, log, s.params.MetricScope, frontendClient)
 
 	log.Infof("%v started", common.WorkerServiceName)
 	<-s.stopC
+	for _, w := range s.sysWorkers {
+		w.Stop()
+	}
 	base.Stop()
 }
 
+// waitUntilReady blocks on common/readiness until every startup dependency is
+// reachable or s.config.StartupDeadline expires, and serves the outcome on
+// s.config.ReadinessHTTPPort's /healthz and /readyz endpoints for the
+// duration of the process so an orchestrator can probe startup instead of the
+// service calling log.Fatal on a cold cluster boot.
+func (s *Service) waitUntilReady(params *service.BootstrapParams, base service.Service, log bark.Logger, frontendClient frontend.Client) error {
+	domainName := sysworkflow.Domain
+	historyClient, err := base.GetClientFactory().NewHistoryClient()
+	if err != nil {
+		return fmt.Errorf("failed to create history client: %v", err)
+	}
+
+	checks := []readiness.Check{
+		&readiness.FuncCheck{
+			CheckName: "frontend-describe-domain",
+			CheckFunc: func(ctx context.Context) error {
+				_, err := frontendClient.DescribeDomain(ctx, &shared.DescribeDomainRequest{Name: &domainName})
+				return err
+			},
+		},
+		&readiness.FuncCheck{
+			CheckName: "history-client-dial",
+			CheckFunc: func(ctx context.Context) error {
+				shardID := int32(0)
+				_, err := historyClient.DescribeHistoryHost(ctx, &shared.DescribeHistoryHostRequest{ShardIdForHost: &shardID})
+				return err
+			},
+		},
+		&readiness.FuncCheck{
+			CheckName: "persistence-ping",
+			CheckFunc: func(ctx context.Context) error {
+				pConfig := params.PersistenceConfig
+				pFactory := persistencefactory.New(&pConfig, params.ClusterMetadata.GetCurrentClusterName(), s.metricsClient, log)
+				metadataManager, err := pFactory.NewMetadataManager(persistencefactory.MetadataV2)
+				if err != nil {
+					return err
+				}
+				metadataManager.Close()
+				return nil
+			},
+		},
+	}
+
+	if params.MessagingClient != nil {
+		consumer, err := params.MessagingClient.NewConsumer(replicator.ReplicationTaskTopic, fmt.Sprintf("%v-readiness", common.WorkerServiceName))
+		if err != nil {
+			return fmt.Errorf("failed to create readiness messaging consumer: %v", err)
+		}
+		defer consumer.Stop()
+
+		// startOnce guards consumer.Start() so a check retried by the backoff
+		// loop below probes the single consumer created above instead of
+		// calling Start on it more than once, whose idempotency isn't
+		// guaranteed.
+		var startOnce sync.Once
+		var startErr error
+		checks = append(checks, &readiness.FuncCheck{
+			CheckName: "messaging-client",
+			CheckFunc: func(ctx context.Context) error {
+				startOnce.Do(func() { startErr = consumer.Start() })
+				return startErr
+			},
+		})
+	}
+
+	readinessHTTPAddr := fmt.Sprintf("127.0.0.1:%v", s.config.ReadinessHTTPPort())
+	s.readinessChecker = readiness.NewChecker(checks, s.config.StartupDeadline(), s.params.MetricScope, log)
+	if _, err := readiness.StartHTTPServer(readinessHTTPAddr, s.readinessChecker); err != nil {
+		log.Warnf("failed to start readiness http server on %v: %v", readinessHTTPAddr, err)
+	}
+
+	return s.readinessChecker.WaitUntilReady(context.Background())
+}
+
 // Stop is called to stop the service
 func (s *Service) Stop() {
 	select {
@@ -131,40 +243,40 @@ func (s *Service) startReplicator(params *service.BootstrapParams, base service.
 	}
 
 	replicator := replicator.NewReplicator(params.ClusterMetadata, metadataManager, history, s.config.ReplicationCfg, params.MessagingClient, log,
-		s.metricsClient)
+		s.newReplicatorScopeFactory(params.MetricScope))
 	if err := replicator.Start(); err != nil {
 		replicator.Stop()
 		log.Fatalf("Fail to start replicator: %v", err)
 	}
 }
 
-func (s *Service) startSysWorker(base service.Service, log bark.Logger, scope tally.Scope) {
-	frontendClient, err := base.GetClientFactory().NewFrontendClient()
-	if err != nil {
-		log.Fatalf("failed to create frontend client: %v", err)
-	}
-	frontendClient = frontend.NewRetryableClient(frontendClient, common.CreateFrontendServiceRetryPolicy(),
-		common.IsWhitelistServiceTransientError)
+func (s *Service) startSysWorker(base service.Service, log bark.Logger, scope tally.Scope, frontendClient frontend.Client) {
+	workersByTaskList := make(map[string]cadenceWorker.Worker)
+	for _, module := range sysworkflow.DefaultRegistry().Modules() {
+		if !s.config.SysWorkflowCfg.IsModuleEnabled(module.Name()) {
+			log.Infof("sysworkflow module %v is disabled, skipping", module.Name())
+			continue
+		}
 
-	s.waitForFrontendStart(frontendClient, log)
-	sysWorker := sysworkflow.NewSysWorker(frontendClient, scope)
-	if err := sysWorker.Start(); err != nil {
-		sysWorker.Stop()
-		log.Fatalf("failed to start sysworker: %v", err)
-	}
-}
+		w, ok := workersByTaskList[module.TaskList()]
+		if !ok {
+			w = cadenceWorker.New(frontendClient, sysworkflow.Domain, module.TaskList(), cadenceWorker.Options{
+				MetricsScope: scope,
+			})
+			workersByTaskList[module.TaskList()] = w
+		}
 
-func (s *Service) waitForFrontendStart(frontendClient frontend.Client, log bark.Logger) {
-	name := sysworkflow.Domain
-	request := &shared.DescribeDomainRequest{
-		Name: &name,
+		module.RegisterWith(w, sysworkflow.ModuleDependencies{
+			FrontendClient: frontendClient,
+			MetricsScope:   scope.Tagged(map[string]string{"workflow_type": module.Name()}),
+		})
 	}
 
-	for i := 0; i < FrontendRetryLimit; i++ {
-		if _, err := frontendClient.DescribeDomain(context.Background(), request); err == nil {
-			return
+	for taskList, w := range workersByTaskList {
+		if err := w.Start(); err != nil {
+			w.Stop()
+			log.Fatalf("failed to start sysworker for task list %v: %v", taskList, err)
 		}
-		<-time.After(PollingDelay)
+		s.sysWorkers = append(s.sysWorkers, w)
 	}
-	log.Fatal("failed to connect to frontend client")
-}
\ No newline at end of file
+}
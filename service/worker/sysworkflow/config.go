@@ -0,0 +1,46 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sysworkflow
+
+import "github.com/uber/cadence/common/service/dynamicconfig"
+
+const (
+	// Domain is the domain system workflows run in
+	Domain = "cadence-system"
+)
+
+type (
+	// Config contains the configuration for the sysworkflow package, including
+	// which registered SysWorkflowModules are enabled for this cluster
+	Config struct {
+		EnabledModules dynamicconfig.ListPropertyFn
+	}
+)
+
+// IsModuleEnabled reports whether name appears in the configured enabled-modules list
+func (c *Config) IsModuleEnabled(name string) bool {
+	for _, enabled := range c.EnabledModules() {
+		if enabledName, ok := enabled.(string); ok && enabledName == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sysworkflow
+
+import (
+	"fmt"
+	"sync"
+
+	cadenceWorker "go.uber.org/cadence/worker"
+)
+
+type (
+	// SysWorkflowModule is a pluggable unit of background processing hosted by
+	// the cadence-worker service's sysworker (archival, tiered-storage
+	// compaction, scheduled batch operations, cluster health probes, etc).
+	// Modules register themselves at init time via Register, and startSysWorker
+	// starts one cadence worker per distinct task list across all modules whose
+	// dynamic-config gate reports enabled.
+	SysWorkflowModule interface {
+		// Name uniquely identifies the module. It is used as the
+		// WorkerSysWorkflowEnabledModules list entry and as a metrics tag.
+		Name() string
+		// TaskList is the Cadence task list this module's workflows and
+		// activities poll on. Modules sharing a task list share a worker.
+		TaskList() string
+		// RegisterWith registers the module's workflow and activity functions
+		// on w, using deps for anything that can only be constructed once the
+		// worker service has started (frontend client, metrics scope, etc).
+		RegisterWith(w cadenceWorker.Worker, deps ModuleDependencies)
+	}
+
+	// Registry holds the set of SysWorkflowModules known to the process
+	Registry struct {
+		mu      sync.Mutex
+		modules map[string]SysWorkflowModule
+	}
+)
+
+// defaultRegistry is the process-wide registry that packages register against
+// from their init() functions
+var defaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty module registry
+func NewRegistry() *Registry {
+	return &Registry{
+		modules: make(map[string]SysWorkflowModule),
+	}
+}
+
+// Register adds module to the default registry. It panics on a duplicate
+// name, mirroring how workflow/activity registration panics on duplicates.
+func Register(module SysWorkflowModule) {
+	defaultRegistry.Register(module)
+}
+
+// Register adds module to r. It panics on a duplicate name.
+func (r *Registry) Register(module SysWorkflowModule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.modules[module.Name()]; ok {
+		panic(fmt.Sprintf("sysworkflow: module %q already registered", module.Name()))
+	}
+	r.modules[module.Name()] = module
+}
+
+// Modules returns every module registered with r
+func (r *Registry) Modules() []SysWorkflowModule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modules := make([]SysWorkflowModule, 0, len(r.modules))
+	for _, module := range r.modules {
+		modules = append(modules, module)
+	}
+	return modules
+}
+
+// DefaultRegistry returns the process-wide registry that Register populates
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sysworkflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cadenceWorker "go.uber.org/cadence/worker"
+)
+
+type fakeModule struct {
+	name     string
+	taskList string
+}
+
+func (m *fakeModule) Name() string { return m.name }
+func (m *fakeModule) TaskList() string { return m.taskList }
+func (m *fakeModule) RegisterWith(w cadenceWorker.Worker, deps ModuleDependencies) {}
+
+func TestRegistry_RegisterAndList(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&fakeModule{name: "a", taskList: "tl-a"})
+	r.Register(&fakeModule{name: "b", taskList: "tl-b"})
+
+	require.Len(t, r.Modules(), 2)
+}
+
+func TestRegistry_DuplicateNamePanics(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&fakeModule{name: "a", taskList: "tl-a"})
+
+	require.Panics(t, func() {
+		r.Register(&fakeModule{name: "a", taskList: "tl-a"})
+	})
+}
+
+func TestDefaultRegistry_HasCoreModuleRegistered(t *testing.T) {
+	found := false
+	for _, module := range DefaultRegistry().Modules() {
+		if module.Name() == CoreModuleName {
+			found = true
+		}
+	}
+	require.True(t, found, "core module should be registered via init()")
+}
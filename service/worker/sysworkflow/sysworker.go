@@ -0,0 +1,117 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sysworkflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/cadence/.gen/go/shared"
+	cadenceWorker "go.uber.org/cadence/worker"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/uber/cadence/client/frontend"
+)
+
+const (
+	// CoreModuleName identifies the sysworkflow module that existed before
+	// the registry was introduced. It ships enabled by default so migrating
+	// to the registry is not a behavior change.
+	CoreModuleName = "cadence-sys-workflow"
+	coreTaskList   = "cadence-sys-tl"
+
+	activityStartToCloseTimeout = 30 * time.Second
+)
+
+type (
+	// ModuleDependencies bundles the runtime dependencies a SysWorkflowModule
+	// needs to register and run its workflows/activities. Modules are
+	// registered against the Registry at init time without these, since the
+	// dependencies are only available once the worker service has started.
+	ModuleDependencies struct {
+		FrontendClient frontend.Client
+		MetricsScope   tally.Scope
+	}
+
+	// coreModule wraps the original, always-on sysworker workflow in the
+	// SysWorkflowModule interface so it goes through the same registry-driven
+	// startup path as every other module.
+	coreModule struct{}
+)
+
+func init() {
+	Register(&coreModule{})
+}
+
+func (m *coreModule) Name() string {
+	return CoreModuleName
+}
+
+func (m *coreModule) TaskList() string {
+	return coreTaskList
+}
+
+func (m *coreModule) RegisterWith(w cadenceWorker.Worker, deps ModuleDependencies) {
+	activities := newCoreActivities(deps.FrontendClient, deps.MetricsScope)
+	w.RegisterWorkflowWithOptions(CoreSysWorkflow, cadenceWorker.RegisterWorkflowOptions{Name: CoreModuleName})
+	w.RegisterActivity(activities.pingFrontend)
+}
+
+// CoreSysWorkflow is the workflow previously started unconditionally by
+// NewSysWorker. It is kept as a module enabled by default so existing
+// deployments see no behavior change after the registry migration.
+func CoreSysWorkflow(ctx workflow.Context) error {
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: activityStartToCloseTimeout,
+	}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	var activities *coreActivities
+	return workflow.ExecuteActivity(ctx, activities.pingFrontend).Get(ctx, nil)
+}
+
+type coreActivities struct {
+	frontendClient frontend.Client
+	metricsScope   tally.Scope
+}
+
+func newCoreActivities(frontendClient frontend.Client, metricsScope tally.Scope) *coreActivities {
+	return &coreActivities{frontendClient: frontendClient, metricsScope: metricsScope}
+}
+
+// pingFrontend confirms the frontend is still reachable from within the
+// sysworkflow domain, the same liveness signal NewSysWorker relied on before
+// the registry migration. It emits through a.metricsScope, the
+// deps.MetricsScope passed to RegisterWith, so every module's activity
+// metrics are tagged with its own module name without coreActivities having
+// to know what that name is.
+func (a *coreActivities) pingFrontend(ctx context.Context) error {
+	sw := a.metricsScope.Timer("ping-frontend-latency").Start()
+	defer sw.Stop()
+
+	domainName := Domain
+	_, err := a.frontendClient.DescribeDomain(ctx, &shared.DescribeDomainRequest{Name: &domainName})
+	if err != nil {
+		a.metricsScope.Counter("ping-frontend-errors").Inc(1)
+	}
+	return err
+}